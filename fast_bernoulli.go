@@ -94,23 +94,27 @@ package go_fast_bernoulli
 import (
 	"fmt"
 	"math"
-	"math/rand"
 	"time"
 )
 
 // New construct a new `FastBernoulli` instance that samples events with the
 // given probability.
-func New(probability float64, r *rand.Rand) (*FastBernoulli, error) {
+//
+// `src` supplies the randomness used to compute skip counts; pass `nil` to
+// get a `XorShift128Plus` seeded from the current time. Any `Source`
+// implementation may be used, including `*math/rand.Rand`, which satisfies
+// the interface out of the box.
+func New(probability float64, src Source) (*FastBernoulli, error) {
 	if probability < 0 || probability > 1 {
 		return nil, fmt.Errorf("invalid probability")
 	}
-	if r == nil {
-		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	if src == nil {
+		src = NewXorShift128Plus(uint64(time.Now().UnixNano()))
 	}
 	f := &FastBernoulli{
 		probability:          probability,
-		invLogNotProbability: 1 / math.Log(1.0-probability),
-		r:                    r,
+		invLogNotProbability: invLogNotProbabilityFor(probability),
+		src:                  src,
 	}
 	f.resetSkipCount()
 	return f, nil
@@ -127,7 +131,7 @@ type FastBernoulli struct {
 	// definitely negative, as required by chooseSkipCount. See setProbability for
 	// the details.
 	invLogNotProbability float64
-	r                    *rand.Rand
+	src                  Source
 
 	skipCount uint32
 }
@@ -184,6 +188,39 @@ func (f *FastBernoulli) Probability() float64 {
 	return f.probability
 }
 
+// SetProbability reconfigures the sampler to sample events with the given
+// probability, without requiring a new `FastBernoulli` to be constructed.
+//
+// This is useful for long-lived samplers whose target rate is tuned at
+// runtime, e.g. in response to a config reload or an adaptive sampling
+// controller. The next call to `Trial` or `MultiTrial` reflects the new
+// rate immediately.
+func (f *FastBernoulli) SetProbability(p float64) error {
+	if p < 0 || p > 1 {
+		return fmt.Errorf("invalid probability")
+	}
+	f.probability = p
+	f.invLogNotProbability = invLogNotProbabilityFor(p)
+	f.resetSkipCount()
+	return nil
+}
+
+// invLogNotProbabilityFor returns 1 / log(1 - p), kept within
+// [-2**53, -1/37) for every p in (0, 1).
+func invLogNotProbabilityFor(p float64) float64 {
+	// Use Log1p(-p) rather than Log(1.0-p): for small p, 1.0-p rounds to
+	// exactly 1.0 in float64, and Log(1.0) is 0, which would send
+	// invLogNotProbability to +Inf instead of staying safely negative.
+	// Log1p computes log(1+x) directly and stays accurate as p -> 0.
+	inv := 1 / math.Log1p(-p)
+	// Below p = 2**-53 the result drops under -2**53, and to -Inf for
+	// denormal p, so clamp it to -2**53.
+	if inv < -(1 << 53) {
+		inv = -(1 << 53)
+	}
+	return inv
+}
+
 // SkipCount return how many events will be skipped until the next event is sampled
 //
 // When `Probability() == 0` this method's return value is
@@ -202,7 +239,7 @@ func (f *FastBernoulli) resetSkipCount() {
 		// Common case: we need to choose a new skip count using the
 		// formula `floor(log(x) / log(1 - P))`, as explained in the
 		// comment at the top of this file.
-		x := f.r.Float64()
+		x := f.src.Float64()
 		skipCount := math.Floor(math.Log(x) * f.invLogNotProbability)
 		if skipCount <= math.MaxUint32 {
 			f.skipCount = uint32(skipCount)
@@ -85,3 +85,44 @@ func TestFastBernoulli_Edge(t *testing.T) {
 		}
 	}
 }
+
+func TestFastBernoulli_SetProbability(t *testing.T) {
+	fb, err := New(0, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fb.Trial() {
+		t.Fatal("expected no samples at probability 0")
+	}
+
+	if err := fb.SetProbability(1); err != nil {
+		t.Fatal(err)
+	}
+	if fb.Probability() != 1 {
+		t.Fatalf("expected probability 1, got %v", fb.Probability())
+	}
+	if !fb.Trial() {
+		t.Fatal("expected every event sampled at probability 1")
+	}
+
+	if err := fb.SetProbability(-1); err == nil {
+		t.Fatal("expected error for out-of-range probability")
+	}
+}
+
+func TestFastBernoulli_SetProbability_TinyProbabilityStaysRare(t *testing.T) {
+	fb, err := New(1, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1e-320 is well within [0, 1], but 1.0-1e-320 rounds to exactly 1.0 in
+	// float64, which would send invLogNotProbability to +Inf if computed as
+	// 1/math.Log(1.0-p) instead of 1/math.Log1p(-p).
+	if err := fb.SetProbability(1e-320); err != nil {
+		t.Fatal(err)
+	}
+	if fb.SkipCount() == 0 {
+		t.Fatalf("expected a large skip count for a near-zero probability, got %v", fb.SkipCount())
+	}
+}
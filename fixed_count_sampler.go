@@ -0,0 +1,127 @@
+package go_fast_bernoulli
+
+import (
+	"fmt"
+	"time"
+)
+
+// NewFixedCount construct a new `FixedCountSampler` that draws exactly `k`
+// samples, uniformly at random and without replacement, from a stream of
+// `N` items.
+//
+// `src` supplies the randomness used to compute skip counts; pass `nil` to
+// get a `XorShift128Plus` seeded from the current time.
+func NewFixedCount(k, N uint64, src Source) (*FixedCountSampler, error) {
+	if k > N {
+		return nil, fmt.Errorf("k cannot exceed N")
+	}
+	if src == nil {
+		src = NewXorShift128Plus(uint64(time.Now().UnixNano()))
+	}
+	f := &FixedCountSampler{
+		remainingSamples: k,
+		remainingItems:   N,
+		src:              src,
+	}
+	f.resetSkipCount()
+	return f, nil
+}
+
+// FixedCountSampler draws exactly `k` samples, uniformly at random and
+// without replacement, from a stream of known length `N`, in one pass and
+// without allocating.
+//
+// This complements `FastBernoulli`'s i.i.d. sampling with a "sample exactly
+// k of N" mode: telemetry pipelines that need to cap a trace at a fixed
+// number of spans, or reservoir-style subsampling of a known-size batch,
+// want this instead of an independent per-item probability.
+type FixedCountSampler struct {
+	remainingSamples uint64
+	remainingItems   uint64
+	src              Source
+
+	// skipCount is how many more items will be rejected before the next
+	// one is accepted, mirroring how FastBernoulli turns per-trial
+	// Bernoulli draws into a geometric skip count. Here the draws come
+	// from the negative hypergeometric distribution instead, since the
+	// acceptance probability changes as items are consumed.
+	skipCount uint64
+
+	// position is the index of the next item to be offered to Trial,
+	// used by Next to report which item was sampled.
+	position uint64
+}
+
+// Trial performs one step of the fixed-count sampling process, reporting
+// whether the current item should be sampled.
+//
+// Call this once per item in the stream, in order; it returns `true`
+// exactly `k` times over `N` calls. Calling it more than `N` times always
+// returns `false`.
+func (f *FixedCountSampler) Trial() bool {
+	if f.remainingItems == 0 {
+		return false
+	}
+	f.position++
+	if f.skipCount > 0 {
+		f.skipCount--
+		f.remainingItems--
+		return false
+	}
+	f.remainingItems--
+	f.remainingSamples--
+	f.resetSkipCount()
+	return true
+}
+
+// Next advances through the stream, skipping unsampled items, and returns
+// the index of the next sampled item.
+//
+// `ok` is `false` once all `N` items have been offered and all `k` samples
+// have been taken.
+func (f *FixedCountSampler) Next() (index uint64, ok bool) {
+	for f.remainingItems > 0 {
+		idx := f.position
+		if f.Trial() {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// Remaining returns how many samples and how many items remain.
+func (f *FixedCountSampler) Remaining() (remainingSamples, remainingItems uint64) {
+	return f.remainingSamples, f.remainingItems
+}
+
+func (f *FixedCountSampler) resetSkipCount() {
+	switch {
+	case f.remainingSamples == 0:
+		// Edge case: we've drawn all k samples; skip everything left.
+		f.skipCount = f.remainingItems
+	case f.remainingSamples == f.remainingItems:
+		// Fast path: every remaining item must be sampled to reach k.
+		f.skipCount = 0
+	default:
+		// Common case: draw a skip count from the negative hypergeometric
+		// distribution. This is the without-replacement analogue of the
+		// geometric skip count FastBernoulli uses: repeatedly perform a
+		// Bernoulli trial with the acceptance probability
+		// remainingSamples/remainingItems, updating remainingItems after
+		// each rejection, until a trial succeeds.
+		var (
+			skip            uint64
+			remainingItems  = f.remainingItems
+			remainingSample = f.remainingSamples
+		)
+		for {
+			p := float64(remainingSample) / float64(remainingItems)
+			if f.src.Float64() < p {
+				break
+			}
+			skip++
+			remainingItems--
+		}
+		f.skipCount = skip
+	}
+}
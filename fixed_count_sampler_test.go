@@ -0,0 +1,60 @@
+package go_fast_bernoulli
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestFixedCountSampler(t *testing.T) {
+	var (
+		r          = rand.New(rand.NewSource(time.Now().UnixNano()))
+		k, N       = uint64(37), uint64(1000)
+		numSampled uint64
+	)
+	fc, err := NewFixedCount(k, N, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < N; i++ {
+		if fc.Trial() {
+			numSampled++
+		}
+	}
+	if numSampled != k {
+		t.Fatalf("expected exactly %v samples, got %v", k, numSampled)
+	}
+	if fc.Trial() {
+		t.Fatal("expected no samples once N trials have been offered")
+	}
+}
+
+func TestFixedCountSampler_Next(t *testing.T) {
+	k, N := uint64(5), uint64(20)
+	fc, err := NewFixedCount(k, N, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var indices []uint64
+	for {
+		idx, ok := fc.Next()
+		if !ok {
+			break
+		}
+		indices = append(indices, idx)
+	}
+	if uint64(len(indices)) != k {
+		t.Fatalf("expected %v sampled indices, got %v", k, len(indices))
+	}
+	for _, idx := range indices {
+		if idx >= N {
+			t.Fatalf("index %v out of range [0, %v)", idx, N)
+		}
+	}
+}
+
+func TestFixedCountSampler_InvalidK(t *testing.T) {
+	if _, err := NewFixedCount(10, 5, nil); err == nil {
+		t.Fatal("expected error when k exceeds N")
+	}
+}
@@ -0,0 +1,91 @@
+package go_fast_bernoulli
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxExponentialMultiple bounds how many multiples of `rate` a single
+// exponential draw may produce, so a pathologically unlucky draw can't stall
+// sampling for an unbounded number of bytes.
+const maxExponentialMultiple = 20
+
+// NewPoissonSampler construct a new `PoissonSampler` that samples, on
+// average, once every `rate` units of whatever the caller is consuming
+// (bytes, events, ...).
+//
+// `src` supplies the randomness used to draw sampling intervals; pass `nil`
+// to get a `XorShift128Plus` seeded from the current time.
+func NewPoissonSampler(rate float64, src Source) (*PoissonSampler, error) {
+	if rate <= 0 {
+		return nil, fmt.Errorf("invalid rate")
+	}
+	if src == nil {
+		src = NewXorShift128Plus(uint64(time.Now().UnixNano()))
+	}
+	p := &PoissonSampler{
+		rate: rate,
+		src:  src,
+	}
+	p.refill()
+	return p, nil
+}
+
+// PoissonSampler samples a stream of units (bytes, events, ...) as a true
+// Poisson process with a configured mean interval `rate` between samples.
+//
+// This is the sampler to reach for when `FastBernoulli.MultiTrial` isn't
+// quite right: `MultiTrial` only answers "does at least one of these `n`
+// units get sampled", while `Consume` also reports where within the call the
+// sampling point fell. Like `MultiTrial`, `Consume` reports at most one
+// sample per call, however many mean intervals `n` spans, and the next
+// interval is drawn from the end of that call. `PoissonSampler` is the
+// sampler allocation and CPU
+// profilers want: it draws the *next sampling point* from an exponential
+// distribution, the same technique the Go runtime's memory profiler uses.
+type PoissonSampler struct {
+	rate float64
+	src  Source
+
+	// remaining is how many more units can be consumed before the next
+	// sample is due. It goes non-positive exactly when a sample occurs.
+	remaining int64
+}
+
+// Consume reports whether a sample occurred somewhere within the next `n`
+// units, and how far past the sampling point `n` overshot.
+//
+// Call this each time the caller advances by `n` units (bytes allocated,
+// bytes read off a stream, ...). When it returns `sampled == true`, a
+// sampling point fell within this call; `overshoot` is how many of the `n`
+// units came after that point, which callers that want to attribute the
+// sample to a specific byte offset can use to locate it.
+func (p *PoissonSampler) Consume(n uint64) (sampled bool, overshoot uint64) {
+	p.remaining -= int64(n)
+	if p.remaining > 0 {
+		return false, 0
+	}
+	overshoot = uint64(-p.remaining)
+	p.refill()
+	return true, overshoot
+}
+
+// Rate get the mean interval, in units, between samples.
+func (p *PoissonSampler) Rate() float64 {
+	return p.rate
+}
+
+func (p *PoissonSampler) refill() {
+	// Draw an exponentially distributed interval with mean `rate`, using
+	// the same technique as the Go runtime's allocation profiler: take a
+	// uniform integer in `[1, 1<<26)` and transform it with `-log(u) *
+	// rate`. Clamp to a maximum multiple of `rate` so an unlucky draw can't
+	// produce an unbounded gap.
+	u := 1 + p.src.Uint64()%((1<<26)-1)
+	interval := -math.Log(float64(u)/(1<<26)) * p.rate
+	if max := p.rate * maxExponentialMultiple; interval > max {
+		interval = max
+	}
+	p.remaining = int64(interval)
+}
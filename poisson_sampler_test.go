@@ -0,0 +1,39 @@
+package go_fast_bernoulli
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPoissonSampler(t *testing.T) {
+	var (
+		r          = rand.New(rand.NewSource(time.Now().UnixNano()))
+		rate       = 1000.0
+		units      = uint64(1000000)
+		expected   = float64(units) / rate
+		tolerance  = expected * 0.3
+		numSampled int
+	)
+	sampler, err := NewPoissonSampler(rate, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var consumed uint64
+	for consumed < units {
+		if sampled, _ := sampler.Consume(100); sampled {
+			numSampled++
+		}
+		consumed += 100
+	}
+	min, max := expected-tolerance, expected+tolerance
+	if float64(numSampled) < min || float64(numSampled) > max {
+		t.Fatalf("expected ~%v samples, found %v (acceptable range is %v to %v)", expected, numSampled, min, max)
+	}
+}
+
+func TestPoissonSampler_InvalidRate(t *testing.T) {
+	if _, err := NewPoissonSampler(0, nil); err == nil {
+		t.Fatal("expected error for non-positive rate")
+	}
+}
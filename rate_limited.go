@@ -0,0 +1,90 @@
+package go_fast_bernoulli
+
+import "time"
+
+// NewRateLimited wraps `fb` with a maximum samples-per-second cap, so a
+// bursty high-frequency event stream can't overwhelm downstream collectors
+// even when `fb`'s configured probability is high.
+//
+// `qps` is both the refill rate and the burst capacity of the internal
+// token bucket.
+func NewRateLimited(fb *FastBernoulli, qps float64) *RateLimited {
+	return &RateLimited{
+		fb:     fb,
+		qps:    qps,
+		burst:  qps,
+		tokens: qps,
+		last:   time.Now(),
+	}
+}
+
+// RateLimited composes a `FastBernoulli` with a token bucket, capping the
+// number of samples it lets through per second.
+//
+// This mirrors the layered sampling policy used in production tracing
+// stacks: a probability decides which events are candidates for sampling,
+// and a rate limit then protects the collector from bursts. It is not safe
+// for concurrent use, for the same reason `FastBernoulli` isn't; see
+// `Sharded` for a concurrency-safe alternative.
+type RateLimited struct {
+	fb  *FastBernoulli
+	qps float64
+
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// Accepted counts trials that passed both the Bernoulli trial and the
+	// rate limit.
+	Accepted uint64
+	// RateLimited counts trials that passed the Bernoulli trial but were
+	// held back by the rate limit.
+	RateLimited uint64
+	// Skipped counts trials that the underlying Bernoulli trial itself
+	// rejected, before the rate limit was even consulted.
+	Skipped uint64
+}
+
+// Trial performs a Bernoulli trial through the underlying `FastBernoulli`,
+// and, if it fires, consumes one token from the rate limiter.
+//
+// Returns `true` only when both the Bernoulli trial fires and a token is
+// available.
+func (r *RateLimited) Trial() bool {
+	if !r.fb.Trial() {
+		r.Skipped++
+		return false
+	}
+	return r.takeToken()
+}
+
+// MultiTrial performs `n` Bernoulli trials at once through the underlying
+// `FastBernoulli`, and, if any of them fire, consumes one token from the
+// rate limiter.
+func (r *RateLimited) MultiTrial(n uint32) bool {
+	if !r.fb.MultiTrial(n) {
+		r.Skipped++
+		return false
+	}
+	return r.takeToken()
+}
+
+func (r *RateLimited) takeToken() bool {
+	r.refill()
+	if r.tokens < 1 {
+		r.RateLimited++
+		return false
+	}
+	r.tokens--
+	r.Accepted++
+	return true
+}
+
+func (r *RateLimited) refill() {
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.qps
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = now
+}
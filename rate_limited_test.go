@@ -0,0 +1,31 @@
+package go_fast_bernoulli
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRateLimited(t *testing.T) {
+	fb, err := New(1, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl := NewRateLimited(fb, 10)
+
+	var numAccepted int
+	for i := 0; i < 1000; i++ {
+		if rl.Trial() {
+			numAccepted++
+		}
+	}
+	if numAccepted > 20 {
+		t.Fatalf("expected rate limit to bind well below 1000 accepts in a tight loop, got %v", numAccepted)
+	}
+	if uint64(numAccepted) != rl.Accepted {
+		t.Fatalf("Accepted counter %v does not match observed accepts %v", rl.Accepted, numAccepted)
+	}
+	if rl.RateLimited == 0 {
+		t.Fatal("expected the rate limit to bind at least once")
+	}
+}
@@ -0,0 +1,144 @@
+package go_fast_bernoulli
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NewSharded construct a new `Sharded` sampler that presents the same
+// `Trial`/`MultiTrial`/`Probability` surface as `FastBernoulli`, but is
+// safe for concurrent use.
+//
+// `FastBernoulli` itself isn't safe for concurrent use, because its skip
+// count and random source are unsynchronized; wrapping a single instance
+// in a mutex would serialize hot paths like allocation or RPC sampling.
+// `Sharded` instead keeps one independent `FastBernoulli` per shard, each
+// with its own random source, and spreads calls across shards so
+// contention stays low.
+//
+// `numShards` selects how many independent `FastBernoulli` instances to
+// keep; 0 defaults to `runtime.GOMAXPROCS(0)`.
+func NewSharded(probability float64, numShards int) (*Sharded, error) {
+	if numShards <= 0 {
+		numShards = runtime.GOMAXPROCS(0)
+	}
+	seed := uint64(time.Now().UnixNano())
+	shards := make([]*shard, numShards)
+	for i := range shards {
+		src := NewXorShift128Plus(seed ^ (uint64(i+1) * 0x9E3779B97F4A7C15))
+		fb, err := New(probability, src)
+		if err != nil {
+			return nil, err
+		}
+		shards[i] = &shard{fb: fb}
+	}
+	return &Sharded{shards: shards}, nil
+}
+
+// shard pairs a single FastBernoulli with the mutex that protects it. Calls
+// are spread across shards by Sharded's round-robin counter, so each mutex
+// sees only its share of the calls; two goroutines can still land on the
+// same shard at once.
+type shard struct {
+	mu sync.Mutex
+	fb *FastBernoulli
+}
+
+// Sharded is a concurrency-safe sampler that dispatches `Trial` and
+// `MultiTrial` calls across a fixed set of independent `FastBernoulli`
+// shards.
+//
+// Each shard is its own independent Bernoulli process at the configured
+// probability, so the sampler as a whole has the same statistical
+// properties as a single `FastBernoulli` would, without the contention a
+// single shared instance would create under concurrent use.
+type Sharded struct {
+	shards []*shard
+
+	// next is a portable, lock-free round-robin counter used to pick a
+	// shard for each call. A per-goroutine P-pinned token would avoid the
+	// per-shard mutex entirely, but isn't portable; the round-robin
+	// counter plus a mutex per shard gets contention down to
+	// roughly 1/len(shards) instead.
+	next uint64
+}
+
+func (s *Sharded) pick() *shard {
+	idx := atomic.AddUint64(&s.next, 1) % uint64(len(s.shards))
+	return s.shards[idx]
+}
+
+// Trial performs a Bernoulli trial on one of the underlying shards.
+//
+// See `FastBernoulli.Trial` for the semantics; this has the same
+// distribution, just spread across shards.
+func (s *Sharded) Trial() bool {
+	sh := s.pick()
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.fb.Trial()
+}
+
+// MultiTrial performs `n` Bernoulli trials at once on one of the underlying
+// shards.
+//
+// See `FastBernoulli.MultiTrial` for the semantics.
+func (s *Sharded) MultiTrial(n uint32) bool {
+	sh := s.pick()
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.fb.MultiTrial(n)
+}
+
+// Probability get the probability with which events are sampled.
+//
+// This is the same for every shard, so it's read from the first one
+// without needing to dispatch.
+func (s *Sharded) Probability() float64 {
+	sh := s.shards[0]
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.fb.Probability()
+}
+
+// SetProbability reconfigures every shard to sample events with the given
+// probability.
+func (s *Sharded) SetProbability(p float64) error {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		err := sh.fb.SetProbability(p)
+		sh.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SkipCount returns the sum of every shard's skip count.
+//
+// This isn't especially meaningful on its own (no single shard is "next"),
+// but gives a cheap aggregate sense of how soon samples are due across the
+// whole sampler; see `SkipCounts` for the per-shard values.
+func (s *Sharded) SkipCount() uint64 {
+	var total uint64
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		total += uint64(sh.fb.SkipCount())
+		sh.mu.Unlock()
+	}
+	return total
+}
+
+// SkipCounts returns each shard's individual skip count, in shard order.
+func (s *Sharded) SkipCounts() []uint32 {
+	counts := make([]uint32, len(s.shards))
+	for i, sh := range s.shards {
+		sh.mu.Lock()
+		counts[i] = sh.fb.SkipCount()
+		sh.mu.Unlock()
+	}
+	return counts
+}
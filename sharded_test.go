@@ -0,0 +1,59 @@
+package go_fast_bernoulli
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSharded(t *testing.T) {
+	var (
+		probability = 0.1
+		numShards   = 4
+		eventsPerG  = 10000
+		numG        = 8
+	)
+	s, err := NewSharded(probability, numShards)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		numSampled uint64
+	)
+	for g := 0; g < numG; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local uint64
+			for i := 0; i < eventsPerG; i++ {
+				if s.Trial() {
+					local++
+				}
+			}
+			atomic.AddUint64(&numSampled, local)
+		}()
+	}
+	wg.Wait()
+
+	var (
+		events         = uint64(numG * eventsPerG)
+		expected       = float64(events) * probability
+		errorTolerance = expected * 0.25
+	)
+	if float64(numSampled) < expected-errorTolerance || float64(numSampled) > expected+errorTolerance {
+		t.Fatalf("expected ~%v samples, found %v", expected, numSampled)
+	}
+
+	if counts := s.SkipCounts(); len(counts) != numShards {
+		t.Fatalf("expected %v per-shard skip counts, got %v", numShards, len(counts))
+	}
+
+	if err := s.SetProbability(1); err != nil {
+		t.Fatal(err)
+	}
+	if s.Probability() != 1 {
+		t.Fatalf("expected probability 1 after SetProbability, got %v", s.Probability())
+	}
+}
@@ -0,0 +1,68 @@
+package go_fast_bernoulli
+
+// Source is the random number source `FastBernoulli` and its variants draw
+// from. Only two operations are needed: a raw 64-bit word, and a float64
+// uniformly distributed over `[0, 1)`.
+//
+// `*math/rand.Rand` already implements this interface, so existing callers
+// that construct a `FastBernoulli` with one keep working unchanged.
+type Source interface {
+	Uint64() uint64
+	Float64() float64
+}
+
+// XorShift128Plus is a `Source` implementation of the xorshift128+ generator.
+//
+// It isn't cryptographically secure, but it's fast and has good enough
+// statistical properties for sampling decisions, which is what the reference
+// implementation of this algorithm uses it for: `Trial()` is called in tight
+// loops, and `math/rand.Rand.Float64` shows up hot in profiles at that call
+// rate.
+type XorShift128Plus struct {
+	s0, s1 uint64
+}
+
+// NewXorShift128Plus constructs a `XorShift128Plus` source seeded from a
+// single `uint64`.
+//
+// The all-zero state is invalid for xorshift generators (it never produces
+// anything but zero), so a zero seed is mixed with a constant before use.
+func NewXorShift128Plus(seed uint64) *XorShift128Plus {
+	if seed == 0 {
+		seed = 0x9E3779B97F4A7C15
+	}
+	s0 := splitMix64(&seed)
+	s1 := splitMix64(&seed)
+	if s0 == 0 && s1 == 0 {
+		s1 = 1
+	}
+	return &XorShift128Plus{s0: s0, s1: s1}
+}
+
+// Uint64 returns the next pseudo-random 64-bit word.
+func (x *XorShift128Plus) Uint64() uint64 {
+	s1 := x.s0
+	s0 := x.s1
+	x.s0 = s0
+	s1 ^= s1 << 23
+	s1 ^= s0 ^ (s1 >> 17) ^ (s0 >> 26)
+	x.s1 = s1
+	return s1 + s0
+}
+
+// Float64 returns a pseudo-random float64 uniformly distributed over
+// `[0, 1)`, taken from the top 53 bits of `Uint64`.
+func (x *XorShift128Plus) Float64() float64 {
+	return float64(x.Uint64()>>11) * (1.0 / (1 << 53))
+}
+
+// splitMix64 advances `*state` and returns a well-mixed 64-bit word, used
+// only to turn a single `uint64` seed into the two words of xorshift128+
+// state.
+func splitMix64(state *uint64) uint64 {
+	*state += 0x9E3779B97F4A7C15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
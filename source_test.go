@@ -0,0 +1,20 @@
+package go_fast_bernoulli
+
+import "testing"
+
+func TestXorShift128Plus(t *testing.T) {
+	src := NewXorShift128Plus(12345)
+	for i := 0; i < 10000; i++ {
+		f := src.Float64()
+		if f < 0 || f >= 1 {
+			t.Fatalf("Float64 returned %v, want value in [0, 1)", f)
+		}
+	}
+}
+
+func TestXorShift128Plus_ZeroSeed(t *testing.T) {
+	src := NewXorShift128Plus(0)
+	if src.s0 == 0 && src.s1 == 0 {
+		t.Fatal("zero seed produced all-zero state")
+	}
+}